@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/thomasmmitchell/doomsday/server"
+)
+
+var listCiphersCmd = &cobra.Command{
+	Use:   "list-ciphers",
+	Short: "List the TLS cipher suites this build of doomsday understands",
+	Long: "Prints every cipher suite known to the Go TLS stack, the protocol " +
+		"versions it's valid for, and whether it's considered insecure, so " +
+		"`tls.cipher_suites` can be configured without guessing at names.",
+	Run: func(cmd *cobra.Command, args []string) {
+		server.ListCiphers(os.Stdout)
+	},
+}
+
+func init() {
+	serverCmd.AddCommand(listCiphersCmd)
+}
@@ -0,0 +1,200 @@
+// Package notify dispatches alerts when cached certificates cross
+// configured expiry thresholds, through one or more sinks (Slack,
+// generic webhook, PagerDuty, SMTP).
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	StateFile  string       `yaml:"state_file"`
+	Thresholds []string     `yaml:"thresholds"`
+	Sinks      []SinkConfig `yaml:"sinks"`
+}
+
+// Item is a single certificate tracked in the doomsday cache, as seen by
+// the notify package. It mirrors the fields server exposes over /v1/cache.
+type Item struct {
+	Backend    string    `json:"backend"`
+	Path       string    `json:"path"`
+	CommonName string    `json:"common_name"`
+	NotAfter   time.Time `json:"not_after"`
+}
+
+type Sink interface {
+	Name() string
+	Notify(threshold time.Duration, items []Item) error
+}
+
+type Notifier struct {
+	sinks      []Sink
+	thresholds []time.Duration
+	state      *state
+}
+
+func New(conf Config) (*Notifier, error) {
+	thresholds := make([]time.Duration, 0, len(conf.Thresholds))
+	for _, raw := range conf.Thresholds {
+		d, err := parseThreshold(raw)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid notification threshold `%s': %s", raw, err)
+		}
+
+		thresholds = append(thresholds, d)
+	}
+
+	sort.Slice(thresholds, func(i, j int) bool { return thresholds[i] < thresholds[j] })
+
+	sinks := make([]Sink, 0, len(conf.Sinks))
+	for _, sinkConf := range conf.Sinks {
+		sink, err := newSink(sinkConf)
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	st, err := loadState(conf.StateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Notifier{sinks: sinks, thresholds: thresholds, state: st}, nil
+}
+
+// Check buckets items by the smallest threshold each has newly crossed
+// since the last Check, and dispatches one notification per sink per
+// bucket. Certs that haven't crossed a threshold, or that already
+// triggered one, are skipped.
+func (n *Notifier) Check(items []Item) error {
+	now := time.Now()
+	buckets := map[time.Duration][]Item{}
+	bucketKeys := map[time.Duration][]stateKey{}
+
+	for _, item := range items {
+		ttl := item.NotAfter.Sub(now)
+
+		var crossed time.Duration
+		var ok bool
+		for _, threshold := range n.thresholds {
+			if ttl <= threshold {
+				crossed, ok = threshold, true
+				break
+			}
+		}
+
+		if !ok {
+			continue
+		}
+
+		key := stateKey{Backend: item.Backend, Path: item.Path, Threshold: crossed}
+		if n.state.Has(key) {
+			continue
+		}
+
+		buckets[crossed] = append(buckets[crossed], item)
+		bucketKeys[crossed] = append(bucketKeys[crossed], key)
+	}
+
+	if len(buckets) == 0 || len(n.sinks) == 0 {
+		return nil
+	}
+
+	// A key is only marked seen once some sink has actually delivered its
+	// bucket; a bucket every sink failed to send is left unmarked so the
+	// next Check retries it instead of silently losing the alert.
+	var lastErr error
+	anyDelivered := false
+	for threshold, bucketItems := range buckets {
+		delivered := false
+		for _, sink := range n.sinks {
+			if err := sink.Notify(threshold, bucketItems); err != nil {
+				lastErr = fmt.Errorf("sink `%s': %s", sink.Name(), err)
+				fmt.Printf("notify: sink `%s' failed to send: %s\n", sink.Name(), err)
+				continue
+			}
+
+			delivered = true
+		}
+
+		if !delivered {
+			continue
+		}
+
+		anyDelivered = true
+		for _, key := range bucketKeys[threshold] {
+			n.state.Mark(key)
+		}
+	}
+
+	if !anyDelivered {
+		return fmt.Errorf("no sink delivered any notification: %s", lastErr)
+	}
+
+	return n.state.Save()
+}
+
+// Test sends one synthetic alert through every configured sink, bypassing
+// thresholds and state, so operators can validate sink configuration.
+func (n *Notifier) Test() error {
+	sample := []Item{{
+		Backend:    "test",
+		Path:       "test/synthetic",
+		CommonName: "doomsday-notification-test",
+		NotAfter:   time.Now().Add(24 * time.Hour),
+	}}
+
+	var firstErr error
+	for _, sink := range n.sinks {
+		if err := sink.Notify(24*time.Hour, sample); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("sink `%s': %s", sink.Name(), err)
+		}
+	}
+
+	return firstErr
+}
+
+// parseThreshold parses a duration as time.ParseDuration would, with one
+// addition: a trailing "d" or "w" unit (e.g. "30d", "7d", "1w"), since
+// time.ParseDuration only understands ns/us/ms/s/m/h and thresholds are
+// naturally expressed in days/weeks.
+func parseThreshold(raw string) (time.Duration, error) {
+	if d, err := time.ParseDuration(raw); err == nil {
+		return d, nil
+	}
+
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(raw, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(raw, "w"):
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, fmt.Errorf("time: unknown unit in duration `%s'", raw)
+	}
+
+	n, err := strconv.ParseFloat(raw[:len(raw)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("time: invalid duration `%s'", raw)
+	}
+
+	return time.Duration(n * float64(unit)), nil
+}
+
+func formatSummary(threshold time.Duration, items []Item) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%d certificate(s) now expiring within %s:\n", len(items), threshold)
+	for _, item := range items {
+		fmt.Fprintf(&buf, "- [%s] %s (%s) expires %s\n",
+			item.Backend, item.Path, item.CommonName, item.NotAfter.Format(time.RFC3339))
+	}
+
+	return buf.String()
+}
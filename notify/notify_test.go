@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseThreshold(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want time.Duration
+	}{
+		{"30d", 30 * 24 * time.Hour},
+		{"7d", 7 * 24 * time.Hour},
+		{"1d", 24 * time.Hour},
+		{"2w", 2 * 7 * 24 * time.Hour},
+		{"720h", 720 * time.Hour},
+		{"90m", 90 * time.Minute},
+	}
+
+	for _, c := range cases {
+		got, err := parseThreshold(c.raw)
+		if err != nil {
+			t.Fatalf("parseThreshold(%q): unexpected error: %s", c.raw, err)
+		}
+
+		if got != c.want {
+			t.Fatalf("parseThreshold(%q) = %s, want %s", c.raw, got, c.want)
+		}
+	}
+
+	if _, err := parseThreshold("not-a-duration"); err == nil {
+		t.Fatal("expected an error for an unparseable threshold")
+	}
+}
+
+type fakeSink struct {
+	name string
+	err  error
+}
+
+func (f *fakeSink) Name() string                       { return f.name }
+func (f *fakeSink) Notify(time.Duration, []Item) error { return f.err }
+
+func testItem() Item {
+	return Item{Backend: "b", Path: "p", NotAfter: time.Now().Add(time.Hour)}
+}
+
+func TestCheckDoesNotMarkOnTotalFailure(t *testing.T) {
+	n := &Notifier{
+		sinks:      []Sink{&fakeSink{name: "a", err: fmt.Errorf("boom")}},
+		thresholds: []time.Duration{24 * time.Hour},
+		state:      &state{seen: map[string]bool{}},
+	}
+
+	if err := n.Check([]Item{testItem()}); err == nil {
+		t.Fatal("expected an error when every sink fails")
+	}
+
+	key := stateKey{Backend: "b", Path: "p", Threshold: 24 * time.Hour}
+	if n.state.Has(key) {
+		t.Fatal("threshold should not be marked seen when no sink delivered")
+	}
+}
+
+func TestCheckMarksOnPartialSuccess(t *testing.T) {
+	n := &Notifier{
+		sinks: []Sink{
+			&fakeSink{name: "broken", err: fmt.Errorf("boom")},
+			&fakeSink{name: "working"},
+		},
+		thresholds: []time.Duration{24 * time.Hour},
+		state:      &state{seen: map[string]bool{}},
+	}
+
+	if err := n.Check([]Item{testItem()}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	key := stateKey{Backend: "b", Path: "p", Threshold: 24 * time.Hour}
+	if !n.state.Has(key) {
+		t.Fatal("threshold should be marked seen once at least one sink delivered")
+	}
+}
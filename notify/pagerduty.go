@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+type PagerDutyConfig struct {
+	RoutingKey string `yaml:"routing_key"`
+}
+
+type pagerDutySink struct {
+	routingKey string
+}
+
+func newPagerDutySink(conf PagerDutyConfig) *pagerDutySink {
+	return &pagerDutySink{routingKey: conf.RoutingKey}
+}
+
+func (p *pagerDutySink) Name() string { return "pagerduty" }
+
+func (p *pagerDutySink) Notify(threshold time.Duration, items []Item) error {
+	body, err := json.Marshal(struct {
+		RoutingKey  string `json:"routing_key"`
+		EventAction string `json:"event_action"`
+		Payload     struct {
+			Summary  string `json:"summary"`
+			Source   string `json:"source"`
+			Severity string `json:"severity"`
+		} `json:"payload"`
+	}{
+		RoutingKey:  p.routingKey,
+		EventAction: "trigger",
+		Payload: struct {
+			Summary  string `json:"summary"`
+			Source   string `json:"source"`
+			Severity string `json:"severity"`
+		}{
+			Summary:  formatSummary(threshold, items),
+			Source:   "doomsday",
+			Severity: "warning",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := sinkHTTPClient.Post(pagerDutyEventsURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("PagerDuty Events API returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
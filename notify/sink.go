@@ -0,0 +1,37 @@
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// sinkTimeout bounds every sink call. Check runs synchronously inside
+// populate(), which the shutdown path waits on, so a hung endpoint must
+// not be able to block a SIGINT/SIGTERM indefinitely.
+const sinkTimeout = 10 * time.Second
+
+var sinkHTTPClient = &http.Client{Timeout: sinkTimeout}
+
+type SinkConfig struct {
+	Type      string          `yaml:"type"`
+	Slack     SlackConfig     `yaml:"slack"`
+	Webhook   WebhookConfig   `yaml:"webhook"`
+	PagerDuty PagerDutyConfig `yaml:"pagerduty"`
+	SMTP      SMTPConfig      `yaml:"smtp"`
+}
+
+func newSink(conf SinkConfig) (Sink, error) {
+	switch conf.Type {
+	case "slack":
+		return newSlackSink(conf.Slack), nil
+	case "webhook":
+		return newWebhookSink(conf.Webhook), nil
+	case "pagerduty":
+		return newPagerDutySink(conf.PagerDuty), nil
+	case "smtp":
+		return newSMTPSink(conf.SMTP), nil
+	default:
+		return nil, fmt.Errorf("Unrecognized notification sink type `%s'", conf.Type)
+	}
+}
@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type SlackConfig struct {
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+type slackSink struct {
+	webhookURL string
+}
+
+func newSlackSink(conf SlackConfig) *slackSink {
+	return &slackSink{webhookURL: conf.WebhookURL}
+}
+
+func (s *slackSink) Name() string { return "slack" }
+
+func (s *slackSink) Notify(threshold time.Duration, items []Item) error {
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{Text: formatSummary(threshold, items)})
+	if err != nil {
+		return err
+	}
+
+	resp, err := sinkHTTPClient.Post(s.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Slack webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+type smtpSink struct {
+	conf SMTPConfig
+}
+
+func newSMTPSink(conf SMTPConfig) *smtpSink {
+	return &smtpSink{conf: conf}
+}
+
+func (s *smtpSink) Name() string { return "smtp" }
+
+// Notify dials and drives the SMTP exchange by hand, rather than calling
+// smtp.SendMail, so the whole conversation is bounded by a single deadline --
+// SendMail has no way to time out a stuck server.
+func (s *smtpSink) Notify(threshold time.Duration, items []Item) error {
+	addr := fmt.Sprintf("%s:%d", s.conf.Host, s.conf.Port)
+
+	conn, err := net.DialTimeout("tcp", addr, sinkTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(sinkTimeout)); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.conf.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.conf.Username != "" {
+		auth := smtp.PlainAuth("", s.conf.Username, s.conf.Password, s.conf.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.conf.From); err != nil {
+		return err
+	}
+
+	for _, to := range s.conf.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Subject: doomsday: certificates expiring within %s\r\n\r\n%s",
+		threshold, formatSummary(threshold, items))
+
+	if _, err := w.Write([]byte(msg)); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+type stateKey struct {
+	Backend   string
+	Path      string
+	Threshold time.Duration
+}
+
+func (k stateKey) String() string {
+	return fmt.Sprintf("%s|%s|%s", k.Backend, k.Path, k.Threshold)
+}
+
+// state is a small on-disk record of (backend, path, threshold) tuples that
+// have already triggered a notification, so restarting doomsday doesn't
+// re-fire alerts for certs that already crossed a threshold.
+type state struct {
+	path string
+	seen map[string]bool
+}
+
+func loadState(path string) (*state, error) {
+	s := &state{path: path, seen: map[string]bool{}}
+	if path == "" {
+		return s, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+
+		return nil, fmt.Errorf("Could not read notification state file: %s", err)
+	}
+
+	if err := json.Unmarshal(b, &s.seen); err != nil {
+		return nil, fmt.Errorf("Could not parse notification state file: %s", err)
+	}
+
+	return s, nil
+}
+
+func (s *state) Has(key stateKey) bool { return s.seen[key.String()] }
+func (s *state) Mark(key stateKey)     { s.seen[key.String()] = true }
+
+func (s *state) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(s.seen)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, b, 0644)
+}
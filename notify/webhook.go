@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type WebhookConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+type webhookSink struct {
+	conf WebhookConfig
+}
+
+func newWebhookSink(conf WebhookConfig) *webhookSink {
+	return &webhookSink{conf: conf}
+}
+
+func (w *webhookSink) Name() string { return "webhook" }
+
+func (w *webhookSink) Notify(threshold time.Duration, items []Item) error {
+	body, err := json.Marshal(struct {
+		Threshold string `json:"threshold"`
+		Items     []Item `json:"items"`
+	}{Threshold: threshold.String(), Items: items})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", w.conf.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.conf.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := sinkHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Webhook POST to `%s' returned status %d", w.conf.URL, resp.StatusCode)
+	}
+
+	return nil
+}
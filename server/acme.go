@@ -0,0 +1,65 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const letsEncryptStagingURL = "https://acme-staging-v02.api.letsencrypt.org/directory"
+
+// acmeListener returns the :443 TLS listener driven by the autocert
+// manager, along with the companion :80 HTTP-01 challenge server it starts.
+// The challenge server is returned, not just fired off in a goroutine, so
+// the caller can Shutdown it alongside the main listener instead of
+// leaking it past SIGINT/SIGTERM.
+func acmeListener(conf *Config) (net.Listener, *http.Server, error) {
+	acmeConf := conf.Server.TLS.ACME
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(acmeConf.Hostnames...),
+		Cache:      autocert.DirCache(acmeConf.CacheDir),
+		Email:      acmeConf.Email,
+	}
+
+	directoryURL := acmeConf.DirectoryURL
+	if directoryURL == "" && acmeConf.Staging {
+		directoryURL = letsEncryptStagingURL
+	}
+
+	if directoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: directoryURL}
+	}
+
+	challengeServer := &http.Server{
+		Addr:    ":80",
+		Handler: manager.HTTPHandler(nil),
+	}
+
+	go func() {
+		if err := challengeServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "ACME HTTP-01 challenge listener exited: %s\n", err)
+		}
+	}()
+
+	ln, err := net.Listen("tcp", ":443")
+	if err != nil {
+		challengeServer.Close()
+		return nil, nil, err
+	}
+
+	tlsConfig := manager.TLSConfig()
+	if err = applyTLSTuning(&conf.Server.TLS, tlsConfig); err != nil {
+		ln.Close()
+		challengeServer.Close()
+		return nil, nil, err
+	}
+
+	return tls.NewListener(ln, tlsConfig), challengeServer, nil
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+)
+
+type AuthType string
+
+const (
+	AuthTypeToken AuthType = "token"
+	AuthTypeCert  AuthType = "cert"
+)
+
+type Config struct {
+	Type  AuthType    `yaml:"type"`
+	Token TokenConfig `yaml:"token"`
+	Cert  CertConfig  `yaml:"cert"`
+}
+
+type Authorizer interface {
+	TokenHandler() func(http.HandlerFunc) http.HandlerFunc
+	LoginHandler() http.HandlerFunc
+	Identifier() AuthType
+}
+
+// ClientCAProvider is implemented by Authorizers that can supply a client
+// CA bundle for the TLS listener to verify peer certificates against. The
+// server type-asserts for it so tls.Config.ClientCAs always comes from
+// whatever the Authorizer parsed out of auth.cert.ca.
+type ClientCAProvider interface {
+	ClientCAPool() *x509.CertPool
+	RequireClientCert() bool
+}
+
+func NewAuth(conf Config) (Authorizer, error) {
+	switch conf.Type {
+	case AuthTypeCert:
+		return newCertAuth(conf.Cert)
+	case AuthTypeToken, "":
+		return newTokenAuth(conf.Token)
+	default:
+		return nil, fmt.Errorf("Unrecognized auth type `%s'", conf.Type)
+	}
+}
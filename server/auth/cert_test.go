@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"testing"
+)
+
+func TestVerifiedIdentity(t *testing.T) {
+	leafWithCN := &x509.Certificate{Subject: pkix.Name{CommonName: "client.example.com"}}
+	leafWithSANOnly := &x509.Certificate{DNSNames: []string{"san.example.com"}}
+	leafWithNeither := &x509.Certificate{}
+
+	cases := []struct {
+		name     string
+		tlsState *tls.ConnectionState
+		identity string
+		ok       bool
+	}{
+		{name: "no TLS state", tlsState: nil, ok: false},
+		{name: "no verified chains", tlsState: &tls.ConnectionState{}, ok: false},
+		{
+			name:     "prefers common name",
+			tlsState: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{leafWithCN}}},
+			identity: "client.example.com",
+			ok:       true,
+		},
+		{
+			name:     "falls back to SAN",
+			tlsState: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{leafWithSANOnly}}},
+			identity: "san.example.com",
+			ok:       true,
+		},
+		{
+			name:     "no CN or SAN",
+			tlsState: &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{leafWithNeither}}},
+			ok:       false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := &http.Request{TLS: c.tlsState}
+
+			identity, ok := verifiedIdentity(r)
+			if ok != c.ok {
+				t.Fatalf("expected ok=%v, got %v", c.ok, ok)
+			}
+
+			if identity != c.identity {
+				t.Fatalf("expected identity %q, got %q", c.identity, identity)
+			}
+		})
+	}
+}
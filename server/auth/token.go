@@ -0,0 +1,41 @@
+package auth
+
+import "net/http"
+
+type TokenConfig struct {
+	Token string `yaml:"token"`
+}
+
+type tokenAuth struct {
+	token string
+}
+
+func newTokenAuth(conf TokenConfig) (Authorizer, error) {
+	return &tokenAuth{token: conf.Token}, nil
+}
+
+func (t *tokenAuth) Identifier() AuthType { return AuthTypeToken }
+
+func (t *tokenAuth) LoginHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("token") != t.token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (t *tokenAuth) TokenHandler() func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+t.token {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
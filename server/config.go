@@ -0,0 +1,91 @@
+package server
+
+import (
+	"time"
+
+	"github.com/thomasmmitchell/doomsday/notify"
+	"github.com/thomasmmitchell/doomsday/server/auth"
+	"github.com/thomasmmitchell/doomsday/storage"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+type Config struct {
+	Server        ServerConfig            `yaml:"server"`
+	Backends      []storage.BackendConfig `yaml:"backends"`
+	Notifications notify.Config           `yaml:"notifications"`
+}
+
+type ServerConfig struct {
+	Port    int           `yaml:"port"`
+	LogFile string        `yaml:"log_file"`
+	Auth    auth.Config   `yaml:"auth"`
+	TLS     TLSConfig     `yaml:"tls"`
+	Metrics MetricsConfig `yaml:"metrics"`
+
+	//ShutdownTimeout bounds how long Start waits for in-flight requests to
+	//drain on SIGINT/SIGTERM; once it passes, Start stops waiting and
+	//returns the timeout error, without closing connections that are still
+	//active. Parsed with time.ParseDuration; defaults to 30s when empty.
+	ShutdownTimeout string `yaml:"shutdown_timeout"`
+}
+
+func (c *ServerConfig) shutdownTimeout() time.Duration {
+	if c.ShutdownTimeout == "" {
+		return defaultShutdownTimeout
+	}
+
+	d, err := time.ParseDuration(c.ShutdownTimeout)
+	if err != nil {
+		return defaultShutdownTimeout
+	}
+
+	return d
+}
+
+// MetricsConfig controls access to the /metrics endpoint used for scraping
+// certificate expiry and populate health into Prometheus. The endpoint is
+// always served; this only decides whether loopback clients skip auth.
+type MetricsConfig struct {
+	AllowUnauthenticatedLocalhost bool `yaml:"allow_unauthenticated_localhost"`
+}
+
+type TLSConfig struct {
+	//Cert and Key are the PEM-encoded cert/key pair, embedded directly in
+	//config. This pair is loaded once at startup; SIGHUP has nothing on
+	//disk to re-read for it, so it can't support live reload. Set
+	//CertFile/KeyFile instead if you need SIGHUP to pick up a renewed
+	//cert without a restart.
+	Cert string      `yaml:"cert"`
+	Key  string      `yaml:"key"`
+	ACME *ACMEConfig `yaml:"acme"`
+
+	//CertFile and KeyFile, if set, load the cert/key pair from disk
+	//instead of Cert/Key, and are re-read on SIGHUP so a renewed
+	//certificate can be picked up without dropping connections.
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+
+	//MinVersion is a version string like "1.2" or "1.3". Defaults to the
+	//Go stdlib default (currently TLS 1.2) when empty.
+	MinVersion string `yaml:"min_version"`
+	//CipherSuites is a list of IANA cipher suite names, e.g.
+	//"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256". Defaults to the Go stdlib's
+	//selection when empty.
+	CipherSuites []string `yaml:"cipher_suites"`
+	//CurvePreferences is a list of elliptic curve names: P256, P384, P521,
+	//or X25519.
+	CurvePreferences         []string `yaml:"curve_preferences"`
+	PreferServerCipherSuites bool     `yaml:"prefer_server_cipher_suites"`
+}
+
+// ACMEConfig drives an autocert.Manager so the server can provision and
+// renew its own TLS certificate against an ACME CA instead of being handed
+// a static cert/key pair.
+type ACMEConfig struct {
+	Hostnames    []string `yaml:"hostnames"`
+	Email        string   `yaml:"email"`
+	CacheDir     string   `yaml:"cache_dir"`
+	DirectoryURL string   `yaml:"directory_url"`
+	Staging      bool     `yaml:"staging"`
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/thomasmmitchell/doomsday"
+)
+
+var (
+	populateDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "doomsday_populate_duration_seconds",
+		Help: "Time it takes to populate the doomsday cache from all configured backends",
+		//DefBuckets tops out at 10s, which a multi-backend populate blows
+		//through routinely -- use a wider exponential range (1s..512s)
+		//so the histogram stays useful instead of piling up in +Inf.
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	})
+
+	populateErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "doomsday_populate_errors_total",
+		Help: "Count of populate runs that returned an error",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(populateDuration, populateErrors)
+}
+
+// certCollector rebuilds its certificate gauges from the live doomsday cache
+// on every scrape, rather than caching values from the last populate, so the
+// metric set always matches what /v1/cache would return.
+type certCollector struct {
+	core *doomsday.Core
+
+	notAfter  *prometheus.Desc
+	expiresIn *prometheus.Desc
+	cacheSize *prometheus.Desc
+}
+
+func newCertCollector(core *doomsday.Core) *certCollector {
+	return &certCollector{
+		core: core,
+		notAfter: prometheus.NewDesc(
+			"doomsday_cert_not_after_seconds",
+			"Unix timestamp at which a tracked certificate expires",
+			[]string{"backend", "path", "common_name"}, nil,
+		),
+		expiresIn: prometheus.NewDesc(
+			"doomsday_cert_expires_in_seconds",
+			"Seconds remaining until a tracked certificate expires",
+			[]string{"backend", "path", "common_name"}, nil,
+		),
+		cacheSize: prometheus.NewDesc(
+			"doomsday_cache_items",
+			"Number of certificates currently tracked in the cache",
+			[]string{"backend"}, nil,
+		),
+	}
+}
+
+func (c *certCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.notAfter
+	ch <- c.expiresIn
+	ch <- c.cacheSize
+}
+
+func (c *certCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	itemsByBackend := map[string]float64{}
+
+	for path, v := range c.core.Cache().Map() {
+		ch <- prometheus.MustNewConstMetric(
+			c.notAfter, prometheus.GaugeValue, float64(v.NotAfter.Unix()),
+			v.Backend, path, v.Subject.CommonName,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			c.expiresIn, prometheus.GaugeValue, v.NotAfter.Sub(now).Seconds(),
+			v.Backend, path, v.Subject.CommonName,
+		)
+
+		itemsByBackend[v.Backend]++
+	}
+
+	for backend, count := range itemsByBackend {
+		ch <- prometheus.MustNewConstMetric(c.cacheSize, prometheus.GaugeValue, count, backend)
+	}
+}
+
+func metricsHandler(conf *ServerConfig, auth func(http.HandlerFunc) http.HandlerFunc) http.HandlerFunc {
+	promHandler := promhttp.Handler()
+
+	if !conf.Metrics.AllowUnauthenticatedLocalhost {
+		return auth(promHandler.ServeHTTP)
+	}
+
+	authed := auth(promHandler.ServeHTTP)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if isLoopback(r) {
+			promHandler.ServeHTTP(w, r)
+			return
+		}
+
+		authed(w, r)
+	}
+}
+
+func isLoopback(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
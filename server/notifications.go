@@ -0,0 +1,35 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/thomasmmitchell/doomsday"
+	"github.com/thomasmmitchell/doomsday/notify"
+)
+
+func testNotifications(notifier *notify.Notifier) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := notifier.Test(); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func cacheNotifyItems(core *doomsday.Core) []notify.Item {
+	data := core.Cache().Map()
+	items := make([]notify.Item, 0, len(data))
+	for path, v := range data {
+		items = append(items, notify.Item{
+			Backend:    v.Backend,
+			Path:       path,
+			CommonName: v.Subject.CommonName,
+			NotAfter:   v.NotAfter,
+		})
+	}
+
+	return items
+}
@@ -1,17 +1,24 @@
 package server
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"sort"
+	"syscall"
 	"time"
 
+	"github.com/coreos/go-systemd/activation"
+	"github.com/coreos/go-systemd/daemon"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/thomasmmitchell/doomsday"
+	"github.com/thomasmmitchell/doomsday/notify"
 	"github.com/thomasmmitchell/doomsday/server/auth"
 	"github.com/thomasmmitchell/doomsday/storage"
 )
@@ -53,21 +60,44 @@ func Start(conf Config) error {
 
 	core.SetCache(doomsday.NewCache())
 
+	if err = prometheus.Register(newCertCollector(core)); err != nil {
+		return fmt.Errorf("Could not register metrics collector: %s", err)
+	}
+
+	notifier, err := notify.New(conf.Notifications)
+	if err != nil {
+		return fmt.Errorf("Could not configure notifications: %s", err)
+	}
+
 	populate := func() {
 		startedAt := time.Now()
 		err := core.Populate()
+		populateDuration.Observe(time.Since(startedAt).Seconds())
 		if err != nil {
+			populateErrors.Inc()
 			fmt.Fprintf(logWriter, "%s: Error populating cache: %s\n", time.Now(), err)
+		} else if err := notifier.Check(cacheNotifyItems(core)); err != nil {
+			fmt.Fprintf(logWriter, "%s: Error dispatching expiry notifications: %s\n", time.Now(), err)
 		}
 		fmt.Printf("Populate took %s\n", time.Since(startedAt))
 	}
 
+	populateStop := make(chan struct{})
+	populateDone := make(chan struct{})
+
 	go func() {
+		defer close(populateDone)
+
 		populate()
 		interval := time.NewTicker(time.Hour)
 		defer interval.Stop()
-		for range interval.C {
-			populate()
+		for {
+			select {
+			case <-interval.C:
+				populate()
+			case <-populateStop:
+				return
+			}
 		}
 	}()
 
@@ -85,37 +115,167 @@ func Start(conf Config) error {
 	router.HandleFunc("/v1/auth", authorizer.LoginHandler()).Methods("POST")
 	router.HandleFunc("/v1/cache", auth(getCache(core))).Methods("GET")
 	router.HandleFunc("/v1/cache/refresh", auth(refreshCache(core))).Methods("POST")
+	router.HandleFunc("/metrics", metricsHandler(&conf.Server, auth)).Methods("GET")
+	router.HandleFunc("/v1/notifications/test", auth(testNotifications(notifier))).Methods("POST")
 
 	fmt.Fprintf(logWriter, "Beginning listening on port %d\n", conf.Server.Port)
 
-	if conf.Server.TLS.Cert != "" || conf.Server.TLS.Key != "" {
-		err = listenAndServeTLS(&conf, router)
-	} else {
-		err = http.ListenAndServe(fmt.Sprintf(":%d", conf.Server.Port), router)
+	var reloader *certReloader
+	var ln net.Listener
+	var challengeServer *http.Server
+
+	switch {
+	case conf.Server.TLS.ACME != nil:
+		ln, challengeServer, err = acmeListener(&conf)
+	case conf.Server.TLS.Cert != "" || conf.Server.TLS.Key != "" ||
+		conf.Server.TLS.CertFile != "" || conf.Server.TLS.KeyFile != "":
+		ln, reloader, err = staticTLSListener(&conf, authorizer)
+	default:
+		ln, err = systemdOrTCPListener(conf.Server.Port)
 	}
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	httpServer := &http.Server{Handler: router}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(ln)
+	}()
+
+	notifySystemd("READY=1")
+	fmt.Fprintf(logWriter, "Ready\n")
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	for {
+		select {
+		case sig := <-sigCh:
+			if sig == syscall.SIGHUP {
+				if reloader == nil {
+					fmt.Fprintf(logWriter, "Received SIGHUP, but server is not serving a reloadable static TLS cert\n")
+					continue
+				}
+
+				if conf.Server.TLS.CertFile == "" || conf.Server.TLS.KeyFile == "" {
+					fmt.Fprintf(logWriter, "Received SIGHUP, but tls.cert_file/tls.key_file are not set -- "+
+						"inline tls.cert/tls.key have nothing on disk to reload\n")
+					continue
+				}
+
+				cert, reloadErr := loadKeyPair(&conf)
+				if reloadErr != nil {
+					fmt.Fprintf(logWriter, "Could not reload TLS cert: %s\n", reloadErr)
+					continue
+				}
+
+				reloader.Reload(cert)
+				fmt.Fprintf(logWriter, "Reloaded TLS cert from disk\n")
+				continue
+			}
+
+			fmt.Fprintf(logWriter, "Received %s, shutting down\n", sig)
+			notifySystemd("STOPPING=1")
+
+			ctx, cancel := context.WithTimeout(context.Background(), conf.Server.shutdownTimeout())
+			shutdownErr := httpServer.Shutdown(ctx)
+			if challengeServer != nil {
+				if cErr := challengeServer.Shutdown(ctx); cErr != nil && shutdownErr == nil {
+					shutdownErr = cErr
+				}
+			}
+			cancel()
+
+			close(populateStop)
+			<-populateDone
+			<-serveErr
+
+			return shutdownErr
+		case err := <-serveErr:
+			close(populateStop)
+			<-populateDone
+
+			if challengeServer != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), conf.Server.shutdownTimeout())
+				challengeServer.Shutdown(ctx)
+				cancel()
+			}
+
+			return err
+		}
+	}
+}
+
+func systemdOrTCPListener(port int) (net.Listener, error) {
+	if os.Getenv("LISTEN_FDS") != "" {
+		listeners, err := activation.Listeners()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(listeners) != 1 {
+			return nil, fmt.Errorf("Expected exactly 1 socket-activated listener, got %d", len(listeners))
+		}
+
+		return listeners[0], nil
+	}
+
+	return net.Listen("tcp", fmt.Sprintf(":%d", port))
 }
 
-func listenAndServeTLS(conf *Config, handler http.Handler) error {
+func notifySystemd(state string) {
+	if _, err := daemon.SdNotify(false, state); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not notify systemd of state `%s': %s\n", state, err)
+	}
+}
+
+// loadKeyPair prefers CertFile/KeyFile on disk, so SIGHUP has something to
+// re-read; it only falls back to the embedded, non-reloadable Cert/Key
+// strings when no file paths are configured.
+func loadKeyPair(conf *Config) (tls.Certificate, error) {
+	if conf.Server.TLS.CertFile != "" || conf.Server.TLS.KeyFile != "" {
+		return tls.LoadX509KeyPair(conf.Server.TLS.CertFile, conf.Server.TLS.KeyFile)
+	}
+
+	return tls.X509KeyPair([]byte(conf.Server.TLS.Cert), []byte(conf.Server.TLS.Key))
+}
+
+func staticTLSListener(conf *Config, authorizer auth.Authorizer) (net.Listener, *certReloader, error) {
 	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", conf.Server.Port))
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	defer ln.Close()
-
-	cert, err := tls.X509KeyPair([]byte(conf.Server.TLS.Cert), []byte(conf.Server.TLS.Key))
+	cert, err := loadKeyPair(conf)
 	if err != nil {
-		return err
+		ln.Close()
+		return nil, nil, err
 	}
 
-	tlsListener := tls.NewListener(ln, &tls.Config{
-		NextProtos:   []string{"http/1.1"},
-		Certificates: []tls.Certificate{cert},
-	})
+	reloader := newCertReloader(cert)
+
+	tlsConfig := &tls.Config{
+		NextProtos:     []string{"http/1.1"},
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if provider, ok := authorizer.(auth.ClientCAProvider); ok {
+		tlsConfig.ClientCAs = provider.ClientCAPool()
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if provider.RequireClientCert() {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	if err = applyTLSTuning(&conf.Server.TLS, tlsConfig); err != nil {
+		ln.Close()
+		return nil, nil, err
+	}
 
-	return http.Serve(tlsListener, handler)
+	return tls.NewListener(ln, tlsConfig), reloader, nil
 }
 
 func getInfo(authType auth.AuthType) func(w http.ResponseWriter, r *http.Request) {
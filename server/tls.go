@@ -0,0 +1,144 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// certReloader lets a SIGHUP handler swap in a freshly-loaded certificate
+// without dropping the listener or any in-flight connections.
+type certReloader struct {
+	mu   sync.RWMutex
+	cert tls.Certificate
+}
+
+func newCertReloader(cert tls.Certificate) *certReloader {
+	return &certReloader{cert: cert}
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cert := r.cert
+	return &cert, nil
+}
+
+func (r *certReloader) Reload(cert tls.Certificate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = cert
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var tlsCurves = map[string]tls.CurveID{
+	"P256":   tls.CurveP256,
+	"P384":   tls.CurveP384,
+	"P521":   tls.CurveP521,
+	"X25519": tls.X25519,
+}
+
+func applyTLSTuning(conf *TLSConfig, tlsConfig *tls.Config) error {
+	if conf.MinVersion != "" {
+		version, ok := tlsVersions[conf.MinVersion]
+		if !ok {
+			return fmt.Errorf("Unrecognized TLS min_version `%s'", conf.MinVersion)
+		}
+
+		tlsConfig.MinVersion = version
+	}
+
+	if len(conf.CipherSuites) > 0 {
+		suites, err := cipherSuitesByName(conf.CipherSuites)
+		if err != nil {
+			return err
+		}
+
+		tlsConfig.CipherSuites = suites
+	}
+
+	if len(conf.CurvePreferences) > 0 {
+		curves := make([]tls.CurveID, 0, len(conf.CurvePreferences))
+		for _, name := range conf.CurvePreferences {
+			curve, ok := tlsCurves[name]
+			if !ok {
+				return fmt.Errorf("Unrecognized TLS curve `%s'", name)
+			}
+
+			curves = append(curves, curve)
+		}
+
+		tlsConfig.CurvePreferences = curves
+	}
+
+	tlsConfig.PreferServerCipherSuites = conf.PreferServerCipherSuites
+
+	if tlsConfig.MinVersion == 0 || tlsConfig.MinVersion >= tls.VersionTLS12 {
+		tlsConfig.NextProtos = append([]string{"h2"}, tlsConfig.NextProtos...)
+	}
+
+	return nil
+}
+
+func cipherSuitesByName(names []string) ([]uint16, error) {
+	known := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	for _, suite := range tls.InsecureCipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("Unrecognized TLS cipher suite `%s'", name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// ListCiphers writes every cipher suite known to the Go TLS stack to w,
+// along with the protocol versions it's valid for and whether it's
+// considered insecure, so operators configuring CipherSuites can discover
+// valid values without guessing.
+func ListCiphers(w io.Writer) {
+	versionName := func(v uint16) string {
+		for name, id := range tlsVersions {
+			if id == v {
+				return name
+			}
+		}
+
+		return fmt.Sprintf("0x%04x", v)
+	}
+
+	printSuite := func(suite *tls.CipherSuite, insecure bool) {
+		versions := make([]string, 0, len(suite.SupportedVersions))
+		for _, v := range suite.SupportedVersions {
+			versions = append(versions, versionName(v))
+		}
+
+		fmt.Fprintf(w, "%-50s versions=%-12v insecure=%v\n", suite.Name, versions, insecure)
+	}
+
+	for _, suite := range tls.CipherSuites() {
+		printSuite(suite, false)
+	}
+
+	for _, suite := range tls.InsecureCipherSuites() {
+		printSuite(suite, true)
+	}
+}
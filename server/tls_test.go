@@ -0,0 +1,64 @@
+package server
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestCipherSuitesByName(t *testing.T) {
+	name := tls.CipherSuites()[0].Name
+	id := tls.CipherSuites()[0].ID
+
+	ids, err := cipherSuitesByName([]string{name})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(ids) != 1 || ids[0] != id {
+		t.Fatalf("expected [%d], got %v", id, ids)
+	}
+
+	if _, err := cipherSuitesByName([]string{"NOT_A_REAL_CIPHER_SUITE"}); err == nil {
+		t.Fatal("expected an error for an unrecognized cipher suite name")
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	first := tls.Certificate{Certificate: [][]byte{[]byte("first")}}
+	second := tls.Certificate{Certificate: [][]byte{[]byte("second")}}
+
+	reloader := newCertReloader(first)
+
+	cert, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if string(cert.Certificate[0]) != "first" {
+		t.Fatalf("expected `first`, got `%s'", cert.Certificate[0])
+	}
+
+	reloader.Reload(second)
+
+	cert, err = reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if string(cert.Certificate[0]) != "second" {
+		t.Fatalf("expected `second` after Reload, got `%s'", cert.Certificate[0])
+	}
+}
+
+func TestCipherSuitesByNameIncludesInsecure(t *testing.T) {
+	insecure := tls.InsecureCipherSuites()[0]
+
+	ids, err := cipherSuitesByName([]string{insecure.Name})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(ids) != 1 || ids[0] != insecure.ID {
+		t.Fatalf("expected [%d], got %v", insecure.ID, ids)
+	}
+}